@@ -0,0 +1,197 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package channel
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/IBM-Blockchain/microfab/internal/pkg/identity/certificate"
+	"github.com/IBM-Blockchain/microfab/internal/pkg/orderer"
+	"github.com/IBM-Blockchain/microfab/internal/pkg/organization"
+	"github.com/hyperledger/fabric-config/configtx"
+	"github.com/pkg/errors"
+)
+
+// Build assembles the config-tx for a new channel from the supplied config, member
+// organizations, and ordering service, builds the channel creation transaction, and
+// submits it to the orderer's broadcast API. It returns the resulting Channel, whose
+// GenesisBlock is the block the orderer returned once the channel was created.
+func Build(config *Config, organizations map[string]*organization.Organization, group *orderer.Group, broadcaster Broadcaster) (*Channel, error) {
+	if len(config.MemberMSPIDs) == 0 {
+		return nil, errors.New("at least one member MSP ID is required to create a channel")
+	}
+	application, err := buildApplication(config, organizations)
+	if err != nil {
+		return nil, err
+	}
+	ordererConfig, err := buildOrderer(config, group)
+	if err != nil {
+		return nil, err
+	}
+	c := configtx.Channel{
+		Consortium:   config.Consortium,
+		Application:  application,
+		Capabilities: config.ChannelCapabilities,
+		Orderer:      ordererConfig,
+	}
+	envelopeBytes, err := configtx.NewMarshaledCreateChannelTx(c, config.Name)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to build create channel transaction")
+	}
+	genesisBlock, err := broadcaster.Broadcast(config.Name, envelopeBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to submit create channel transaction to orderer")
+	}
+	return &Channel{
+		name:          config.Name,
+		config:        config,
+		genesisBlock:  genesisBlock,
+		organizations: organizations,
+	}, nil
+}
+
+// buildOrderer translates the orderer group's consensus configuration (solo or
+// etcdraft, as decided by orderer.BuildConsensus) into the orderer section of the
+// channel config-tx, including the consenter set and raft options for etcdraft.
+func buildOrderer(config *Config, group *orderer.Group) (configtx.Orderer, error) {
+	addresses := make([]string, len(group.Orderers()))
+	for i, o := range group.Orderers() {
+		addresses[i] = fmt.Sprintf("%s:%d", o.Hostname(), o.Port())
+	}
+	ord := configtx.Orderer{
+		Addresses:    addresses,
+		Capabilities: config.OrdererCapabilities,
+		BatchSize: configtx.BatchSize{
+			MaxMessageCount:   config.BatchSize.MaxMessageCount,
+			AbsoluteMaxBytes:  config.BatchSize.AbsoluteMaxBytes,
+			PreferredMaxBytes: config.BatchSize.PreferredMaxBytes,
+		},
+		BatchTimeout: config.BatchTimeout,
+	}
+	consensus := group.Consensus()
+	if consensus.Type != orderer.ConsensusTypeEtcdRaft {
+		ord.OrdererType = "solo"
+		return ord, nil
+	}
+	ord.OrdererType = "etcdraft"
+	consenters := make([]configtx.Consenter, len(consensus.Consenters))
+	for i, cst := range consensus.Consenters {
+		clientCert, err := parseCertificatePEM(cst.ClientTLSCert)
+		if err != nil {
+			return configtx.Orderer{}, errors.WithMessagef(err, "failed to parse client TLS cert for consenter %s", cst.Host)
+		}
+		serverCert, err := parseCertificatePEM(cst.ServerTLSCert)
+		if err != nil {
+			return configtx.Orderer{}, errors.WithMessagef(err, "failed to parse server TLS cert for consenter %s", cst.Host)
+		}
+		consenters[i] = configtx.Consenter{
+			Address:       configtx.Address{Host: cst.Host, Port: int(cst.Port)},
+			ClientTLSCert: clientCert,
+			ServerTLSCert: serverCert,
+		}
+	}
+	ord.EtcdRaft = configtx.EtcdRaft{
+		Consenters: consenters,
+		Options: configtx.EtcdRaftOptions{
+			TickInterval:         consensus.RaftOptions.TickInterval.String(),
+			ElectionTick:         consensus.RaftOptions.ElectionTick,
+			HeartbeatTick:        consensus.RaftOptions.HeartbeatTick,
+			MaxInflightBlocks:    consensus.RaftOptions.MaxInflightBlocks,
+			SnapshotIntervalSize: consensus.RaftOptions.SnapshotIntervalSize,
+		},
+	}
+	return ord, nil
+}
+
+func parseCertificatePEM(certPEM []byte) (*x509.Certificate, error) {
+	block, _ := pem.Decode(certPEM)
+	if block == nil {
+		return nil, errors.New("failed to decode PEM block")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+func buildApplication(config *Config, organizations map[string]*organization.Organization) (configtx.Application, error) {
+	orgs := make([]configtx.Organization, 0, len(config.MemberMSPIDs))
+	for _, mspID := range config.MemberMSPIDs {
+		org, ok := organizations[mspID]
+		if !ok {
+			return configtx.Application{}, errors.Errorf("no organization found for MSP ID %s", mspID)
+		}
+		mspConfig, err := buildMSP(mspID, org)
+		if err != nil {
+			return configtx.Application{}, err
+		}
+		orgs = append(orgs, configtx.Organization{
+			Name:     org.Name(),
+			MSP:      mspConfig,
+			Policies: configtx.OrganizationStandardPolicies(),
+		})
+	}
+	policies := map[string]configtx.Policy{}
+	for name, policy := range config.ApplicationPolicies {
+		policies[name] = configtx.Policy{Type: policy.Type, Rule: policy.Rule}
+	}
+	return configtx.Application{
+		Organizations: orgs,
+		Capabilities:  config.ApplicationCapabilities,
+		Policies:      policies,
+	}, nil
+}
+
+// buildMSP translates an organization's MSP definition, including its enrollment CA
+// chain and its (possibly separate) TLS CA chain, into the configtx.MSP consumed by
+// the channel config. This is what feeds the AppRootCAsByChain/OrdererRootCAsByChain
+// split in the resulting channel config.
+func buildMSP(mspID string, org *organization.Organization) (configtx.MSP, error) {
+	rootCerts, err := parseCertificates(org.MSP().RootCertificates())
+	if err != nil {
+		return configtx.MSP{}, errors.WithMessagef(err, "failed to parse root certificates for MSP %s", mspID)
+	}
+	intermediateCerts, err := parseCertificates(org.MSP().IntermediateCertificates())
+	if err != nil {
+		return configtx.MSP{}, errors.WithMessagef(err, "failed to parse intermediate certificates for MSP %s", mspID)
+	}
+	tlsRootCerts, err := parseCertificates(org.MSP().TLSRootCertificates())
+	if err != nil {
+		return configtx.MSP{}, errors.WithMessagef(err, "failed to parse TLS root certificates for MSP %s", mspID)
+	}
+	tlsIntermediateCerts, err := parseCertificates(org.MSP().TLSIntermediateCertificates())
+	if err != nil {
+		return configtx.MSP{}, errors.WithMessagef(err, "failed to parse TLS intermediate certificates for MSP %s", mspID)
+	}
+	adminCerts, err := parseCertificates(org.MSP().AdminCertificates())
+	if err != nil {
+		return configtx.MSP{}, errors.WithMessagef(err, "failed to parse admin certificates for MSP %s", mspID)
+	}
+	return configtx.MSP{
+		Name:                 mspID,
+		RootCerts:            rootCerts,
+		IntermediateCerts:    intermediateCerts,
+		Admins:               adminCerts,
+		TLSRootCerts:         tlsRootCerts,
+		TLSIntermediateCerts: tlsIntermediateCerts,
+	}, nil
+}
+
+func parseCertificates(certs []*certificate.Certificate) ([]*x509.Certificate, error) {
+	parsed := make([]*x509.Certificate, len(certs))
+	for i, c := range certs {
+		x509Cert, err := parseCertificatePEM(c.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		parsed[i] = x509Cert
+	}
+	return parsed, nil
+}
+
+// Broadcaster submits a signed channel creation envelope to the ordering service and
+// waits for the resulting genesis block to be delivered back.
+type Broadcaster interface {
+	Broadcast(channel string, envelope []byte) (genesisBlock []byte, err error)
+}
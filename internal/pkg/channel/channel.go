@@ -0,0 +1,89 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package channel implements an explicit channel lifecycle for microfab: building the
+// config-tx for a new channel, submitting it to the ordering service, and joining
+// individual peers to it after the network has already started.
+package channel
+
+import (
+	"time"
+
+	"github.com/IBM-Blockchain/microfab/internal/pkg/organization"
+)
+
+// Policy describes a single application policy on the channel, such as the
+// Endorsement policy or a custom policy referenced by chaincode endorsement.
+type Policy struct {
+	Type string `json:"type"` // "Signature" or "ImplicitMeta"
+	Rule string `json:"rule"`
+}
+
+// Config describes the configuration of a channel to be created.
+type Config struct {
+	Name                    string              `json:"name"`
+	Consortium              string              `json:"consortium"`
+	MemberMSPIDs            []string            `json:"members"`
+	ApplicationCapabilities []string            `json:"applicationCapabilities"`
+	ChannelCapabilities     []string            `json:"channelCapabilities"`
+	OrdererCapabilities     []string            `json:"ordererCapabilities"`
+	ApplicationPolicies     map[string]Policy `json:"applicationPolicies"`
+	BatchSize               BatchSize         `json:"batchSize"`
+	BatchTimeout            time.Duration     `json:"batchTimeout"`
+}
+
+// BatchSize mirrors the orderer's batch size configuration.
+type BatchSize struct {
+	MaxMessageCount   uint32 `json:"maxMessageCount"`
+	AbsoluteMaxBytes  uint32 `json:"absoluteMaxBytes"`
+	PreferredMaxBytes uint32 `json:"preferredMaxBytes"`
+}
+
+// DefaultBatchSize returns the batch size microfab uses unless the channel request
+// overrides it.
+func DefaultBatchSize() BatchSize {
+	return BatchSize{
+		MaxMessageCount:   10,
+		AbsoluteMaxBytes:  99 * 1024 * 1024,
+		PreferredMaxBytes: 512 * 1024,
+	}
+}
+
+// DefaultBatchTimeout returns the batch timeout microfab uses unless the channel
+// request overrides it.
+func DefaultBatchTimeout() time.Duration {
+	return 2 * time.Second
+}
+
+// Channel represents a channel that has been created on the ordering service.
+type Channel struct {
+	name          string
+	config        *Config
+	genesisBlock  []byte
+	organizations map[string]*organization.Organization
+	joinedPeers   map[string]bool
+}
+
+// Name returns the name of the channel.
+func (c *Channel) Name() string {
+	return c.name
+}
+
+// GenesisBlock returns the marshalled genesis block for the channel, as returned by the
+// orderer when the channel was created.
+func (c *Channel) GenesisBlock() []byte {
+	return c.genesisBlock
+}
+
+// HasJoined returns true if the named peer has already joined this channel.
+func (c *Channel) HasJoined(peerName string) bool {
+	return c.joinedPeers[peerName]
+}
+
+func (c *Channel) markJoined(peerName string) {
+	if c.joinedPeers == nil {
+		c.joinedPeers = map[string]bool{}
+	}
+	c.joinedPeers[peerName] = true
+}
@@ -0,0 +1,80 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package channel
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IBM-Blockchain/microfab/internal/pkg/orderer"
+	"github.com/IBM-Blockchain/microfab/internal/pkg/organization"
+	"github.com/IBM-Blockchain/microfab/internal/pkg/peer"
+)
+
+// Registry is implemented by the microfab console to look up organizations, peers, the
+// ordering service, and previously created channels by name.
+type Registry interface {
+	Organizations() map[string]*organization.Organization
+	Peer(name string) (*peer.Peer, bool)
+	OrdererGroup() *orderer.Group
+	Channel(name string) (*Channel, bool)
+	AddChannel(c *Channel)
+	Broadcaster() Broadcaster
+}
+
+// CreateHandler returns an http.HandlerFunc suitable for mounting at
+// POST /ak/api/v1/channels. The request body is a Config; on success it responds with
+// the created Channel's genesis block, base64-encoded by the JSON encoder.
+func CreateHandler(registry Registry) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		config := &Config{BatchSize: DefaultBatchSize(), BatchTimeout: DefaultBatchTimeout()}
+		if err := json.NewDecoder(r.Body).Decode(config); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if config.Name == "" {
+			http.Error(w, "name is required", http.StatusBadRequest)
+			return
+		}
+		if _, exists := registry.Channel(config.Name); exists {
+			http.Error(w, "channel already exists", http.StatusConflict)
+			return
+		}
+		c, err := Build(config, registry.Organizations(), registry.OrdererGroup(), registry.Broadcaster())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		registry.AddChannel(c)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			Name         string `json:"name"`
+			GenesisBlock []byte `json:"genesisBlock"`
+		}{c.Name(), c.GenesisBlock()})
+	}
+}
+
+// JoinHandler returns an http.HandlerFunc suitable for mounting at
+// POST /ak/api/v1/channels/{name}/peers/{peer}. name and peerName are the path
+// parameters extracted by the console's router.
+func JoinHandler(registry Registry, name, peerName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c, ok := registry.Channel(name)
+		if !ok {
+			http.Error(w, "channel not found", http.StatusNotFound)
+			return
+		}
+		p, ok := registry.Peer(peerName)
+		if !ok {
+			http.Error(w, "peer not found", http.StatusNotFound)
+			return
+		}
+		if err := c.Join(p); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,31 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package channel
+
+import (
+	"github.com/IBM-Blockchain/microfab/internal/pkg/peer"
+	"github.com/pkg/errors"
+)
+
+// Join joins the named peer to the channel. It submits the genesis block already held
+// by the Channel (the one the orderer returned when this process created it) to the
+// peer's CSCC JoinChain, using the peer's own gRPC connection. The genesis block is not
+// fetched from the orderer: a Channel that holds no genesis block returns an error.
+func (c *Channel) Join(p *peer.Peer) error {
+	if c.genesisBlock == nil {
+		return errors.Errorf("no genesis block available for channel %s", c.name)
+	}
+	connection, err := peer.Connect(p, p.MSPID(), p.Organization().Admin())
+	if err != nil {
+		return errors.WithMessage(err, "failed to connect to peer to join channel")
+	}
+	defer connection.Close()
+	err = connection.JoinChannel(c.genesisBlock)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to join peer %s to channel %s", p.Hostname(), c.name)
+	}
+	c.markJoined(p.Hostname())
+	return nil
+}
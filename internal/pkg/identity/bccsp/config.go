@@ -0,0 +1,72 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package bccsp describes which cryptographic service provider a peer or orderer's
+// identity keys are generated and stored with: the default software provider, or a
+// PKCS#11 backed HSM.
+package bccsp
+
+// ProviderType identifies a BCCSP implementation.
+type ProviderType string
+
+const (
+	// ProviderSW generates and stores keys in software, in the MSP's keystore/ directory.
+	ProviderSW ProviderType = "SW"
+
+	// ProviderPKCS11 generates and stores keys inside a PKCS#11 HSM; only the key's SKI
+	// is kept outside the HSM, and no keystore/ directory is written.
+	ProviderPKCS11 ProviderType = "PKCS11"
+)
+
+// PKCS11Config holds the connection details for a PKCS#11 HSM.
+type PKCS11Config struct {
+	Library string
+	Label   string
+	Pin     string
+}
+
+// Config describes the BCCSP configuration for a single peer or orderer.
+type Config struct {
+	Provider ProviderType
+	Hash     string
+	Security int
+	PKCS11   *PKCS11Config
+}
+
+// DefaultConfig returns the software BCCSP configuration microfab has always used.
+func DefaultConfig() *Config {
+	return &Config{
+		Provider: ProviderSW,
+		Hash:     "SHA2",
+		Security: 256,
+	}
+}
+
+// UsesHSM returns true if this configuration generates and stores keys in a PKCS#11
+// HSM rather than on the local filesystem.
+func (c *Config) UsesHSM() bool {
+	return c != nil && c.Provider == ProviderPKCS11
+}
+
+// Section builds the BCCSP section of a core.yaml or orderer.yaml, ready to be merged
+// into the parsed configuration map.
+func (c *Config) Section() map[interface{}]interface{} {
+	section := map[interface{}]interface{}{
+		"Default": string(c.Provider),
+		"SW": map[interface{}]interface{}{
+			"Hash":     c.Hash,
+			"Security": c.Security,
+		},
+	}
+	if c.UsesHSM() {
+		section["PKCS11"] = map[interface{}]interface{}{
+			"Library":  c.PKCS11.Library,
+			"Label":    c.PKCS11.Label,
+			"Pin":      c.PKCS11.Pin,
+			"Hash":     c.Hash,
+			"Security": c.Security,
+		}
+	}
+	return section
+}
@@ -0,0 +1,73 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bccsp
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDefaultConfigUsesSoftwareProvider(t *testing.T) {
+	config := DefaultConfig()
+	if config.Provider != ProviderSW {
+		t.Errorf("expected provider %s, got %s", ProviderSW, config.Provider)
+	}
+	if config.UsesHSM() {
+		t.Error("expected the default config not to use an HSM")
+	}
+}
+
+func TestUsesHSM(t *testing.T) {
+	if (&Config{Provider: ProviderSW}).UsesHSM() {
+		t.Error("expected ProviderSW not to use an HSM")
+	}
+	if !(&Config{Provider: ProviderPKCS11}).UsesHSM() {
+		t.Error("expected ProviderPKCS11 to use an HSM")
+	}
+	var nilConfig *Config
+	if nilConfig.UsesHSM() {
+		t.Error("expected a nil config not to use an HSM")
+	}
+}
+
+func TestSectionSoftwareProvider(t *testing.T) {
+	config := &Config{Provider: ProviderSW, Hash: "SHA2", Security: 256}
+	expected := map[interface{}]interface{}{
+		"Default": "SW",
+		"SW": map[interface{}]interface{}{
+			"Hash":     "SHA2",
+			"Security": 256,
+		},
+	}
+	if section := config.Section(); !reflect.DeepEqual(section, expected) {
+		t.Errorf("expected %+v, got %+v", expected, section)
+	}
+}
+
+func TestSectionPKCS11Provider(t *testing.T) {
+	config := &Config{
+		Provider: ProviderPKCS11,
+		Hash:     "SHA2",
+		Security: 256,
+		PKCS11:   &PKCS11Config{Library: "/usr/lib/softhsm/libsofthsm2.so", Label: "microfab", Pin: "98765432"},
+	}
+	expected := map[interface{}]interface{}{
+		"Default": "PKCS11",
+		"SW": map[interface{}]interface{}{
+			"Hash":     "SHA2",
+			"Security": 256,
+		},
+		"PKCS11": map[interface{}]interface{}{
+			"Library":  "/usr/lib/softhsm/libsofthsm2.so",
+			"Label":    "microfab",
+			"Pin":      "98765432",
+			"Hash":     "SHA2",
+			"Security": 256,
+		},
+	}
+	if section := config.Section(); !reflect.DeepEqual(section, expected) {
+		t.Errorf("expected %+v, got %+v", expected, section)
+	}
+}
@@ -0,0 +1,96 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package bccsp
+
+import (
+	"crypto/sha256"
+	"encoding/asn1"
+
+	"github.com/miekg/pkcs11"
+	"github.com/pkg/errors"
+)
+
+// GenerateECKey generates an ECDSA P-256 key pair inside the PKCS#11 HSM described by
+// this configuration and returns the subject key identifier (SKI) of the public key.
+// The private key never leaves the HSM; the SKI is the only reference to it that is
+// written to disk, in place of the keystore/key.pem file the software provider writes.
+func (c *PKCS11Config) GenerateECKey() (ski []byte, err error) {
+	ctx := pkcs11.New(c.Library)
+	if ctx == nil {
+		return nil, errors.Errorf("failed to load PKCS#11 library %s", c.Library)
+	}
+	if err := ctx.Initialize(); err != nil {
+		return nil, errors.WithMessage(err, "failed to initialize PKCS#11 library")
+	}
+	defer ctx.Finalize()
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to list PKCS#11 slots")
+	}
+	if len(slots) == 0 {
+		return nil, errors.New("no PKCS#11 slots with a token present")
+	}
+	session, err := ctx.OpenSession(slots[0], pkcs11.CKF_SERIAL_SESSION|pkcs11.CKF_RW_SESSION)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to open PKCS#11 session")
+	}
+	defer ctx.CloseSession(session)
+	if err := ctx.Login(session, pkcs11.CKU_USER, c.Pin); err != nil {
+		return nil, errors.WithMessage(err, "failed to login to PKCS#11 token")
+	}
+	defer ctx.Logout(session)
+	publicKeyTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_PARAMS, []byte{0x06, 0x08, 0x2a, 0x86, 0x48, 0xce, 0x3d, 0x03, 0x01, 0x07}),
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_VERIFY, true),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, c.Label),
+	}
+	privateKeyTemplate := []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_TOKEN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_PRIVATE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SENSITIVE, true),
+		pkcs11.NewAttribute(pkcs11.CKA_SIGN, true),
+		pkcs11.NewAttribute(pkcs11.CKA_EXTRACTABLE, false),
+		pkcs11.NewAttribute(pkcs11.CKA_LABEL, c.Label),
+	}
+	publicKeyHandle, privateKeyHandle, err := ctx.GenerateKeyPair(
+		session,
+		[]*pkcs11.Mechanism{pkcs11.NewMechanism(pkcs11.CKM_EC_KEY_PAIR_GEN, nil)},
+		publicKeyTemplate,
+		privateKeyTemplate,
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to generate key pair in PKCS#11 token")
+	}
+	ski, err = skiFromPublicKey(ctx, session, publicKeyHandle)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to derive SKI of generated PKCS#11 key")
+	}
+	idAttr := []*pkcs11.Attribute{pkcs11.NewAttribute(pkcs11.CKA_ID, ski)}
+	if err := ctx.SetAttributeValue(session, privateKeyHandle, idAttr); err != nil {
+		return nil, errors.WithMessage(err, "failed to set CKA_ID on generated private key")
+	}
+	if err := ctx.SetAttributeValue(session, publicKeyHandle, idAttr); err != nil {
+		return nil, errors.WithMessage(err, "failed to set CKA_ID on generated public key")
+	}
+	return ski, nil
+}
+
+// skiFromPublicKey derives the subject key identifier the same way Fabric's software
+// BCCSP does: the SHA-256 digest of the marshalled EC point.
+func skiFromPublicKey(ctx *pkcs11.Ctx, session pkcs11.SessionHandle, publicKeyHandle pkcs11.ObjectHandle) ([]byte, error) {
+	attrs, err := ctx.GetAttributeValue(session, publicKeyHandle, []*pkcs11.Attribute{
+		pkcs11.NewAttribute(pkcs11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil, err
+	}
+	var ecPoint []byte
+	if _, err := asn1.Unmarshal(attrs[0].Value, &ecPoint); err != nil {
+		ecPoint = attrs[0].Value
+	}
+	hash := sha256.Sum256(ecPoint)
+	return hash[:], nil
+}
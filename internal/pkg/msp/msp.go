@@ -10,14 +10,20 @@ import (
 
 // MSP represents a loaded MSP definition.
 type MSP struct {
-	mspID             string
-	rootCertificates  []*certificate.Certificate
-	adminCertificates []*certificate.Certificate
+	mspID                       string
+	rootCertificates            []*certificate.Certificate
+	intermediateCertificates    []*certificate.Certificate
+	adminCertificates           []*certificate.Certificate
+	tlsRootCertificates         []*certificate.Certificate
+	tlsIntermediateCertificates []*certificate.Certificate
 }
 
-// New creates a new MSP.
-func New(mspID string, rootCertificates, adminCertificates []*certificate.Certificate) (*MSP, error) {
-	return &MSP{mspID, rootCertificates, adminCertificates}, nil
+// New creates a new MSP. intermediateCertificates and tlsIntermediateCertificates may
+// be empty if the organization does not use an intermediate CA chain for its
+// enrollment and/or TLS certificates. tlsRootCertificates may be the same as
+// rootCertificates if the organization does not use a separate TLS CA.
+func New(mspID string, rootCertificates, intermediateCertificates, adminCertificates, tlsRootCertificates, tlsIntermediateCertificates []*certificate.Certificate) (*MSP, error) {
+	return &MSP{mspID, rootCertificates, intermediateCertificates, adminCertificates, tlsRootCertificates, tlsIntermediateCertificates}, nil
 }
 
 // ID returns the ID of the MSP.
@@ -30,7 +36,26 @@ func (m *MSP) RootCertificates() []*certificate.Certificate {
 	return m.rootCertificates
 }
 
+// IntermediateCertificates returns the intermediate CA certificates of the MSP, if any.
+func (m *MSP) IntermediateCertificates() []*certificate.Certificate {
+	return m.intermediateCertificates
+}
+
 // AdminCertificates returns the admin certificates of the MSP.
 func (m *MSP) AdminCertificates() []*certificate.Certificate {
 	return m.adminCertificates
 }
+
+// TLSRootCertificates returns the TLS CA root certificates of the MSP. These feed into
+// the AppRootCAsByChain/OrdererRootCAsByChain section of the channel config, and may be
+// the same certificates as RootCertificates if the organization does not use a
+// dedicated TLS CA.
+func (m *MSP) TLSRootCertificates() []*certificate.Certificate {
+	return m.tlsRootCertificates
+}
+
+// TLSIntermediateCertificates returns the TLS CA intermediate certificates of the MSP,
+// if any.
+func (m *MSP) TLSIntermediateCertificates() []*certificate.Certificate {
+	return m.tlsIntermediateCertificates
+}
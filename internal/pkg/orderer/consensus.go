@@ -0,0 +1,85 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package orderer
+
+import "time"
+
+// ConsensusType identifies the ordering service implementation used by a channel.
+type ConsensusType string
+
+const (
+	// ConsensusTypeSolo is the legacy single-node consensus implementation.
+	ConsensusTypeSolo ConsensusType = "solo"
+
+	// ConsensusTypeEtcdRaft is the Raft based crash fault tolerant consensus implementation.
+	ConsensusTypeEtcdRaft ConsensusType = "etcdraft"
+)
+
+// RaftOptions holds the tuning parameters for an etcdraft ordering service, as stored
+// in the channel genesis block.
+type RaftOptions struct {
+	TickInterval         time.Duration
+	ElectionTick         uint32
+	HeartbeatTick        uint32
+	MaxInflightBlocks    uint32
+	SnapshotIntervalSize uint32
+}
+
+// DefaultRaftOptions returns the raft options microfab uses unless overridden by the
+// user supplied microfab.json.
+func DefaultRaftOptions() *RaftOptions {
+	return &RaftOptions{
+		TickInterval:         500 * time.Millisecond,
+		ElectionTick:         10,
+		HeartbeatTick:        1,
+		MaxInflightBlocks:    5,
+		SnapshotIntervalSize: 16 * 1024 * 1024,
+	}
+}
+
+// Consenter describes a single member of an etcdraft consenter set, as it appears in
+// the channel genesis block.
+type Consenter struct {
+	MSPID         string
+	Host          string
+	Port          int32
+	ClientTLSCert []byte
+	ServerTLSCert []byte
+}
+
+// Consensus describes the consensus configuration for the ordering service as a whole.
+type Consensus struct {
+	Type        ConsensusType
+	RaftOptions *RaftOptions
+	Consenters  []*Consenter
+}
+
+// BuildConsensus inspects the supplied orderers and builds the consensus configuration
+// that should be embedded in the channel genesis block. A single orderer always uses
+// solo; more than one orderer always uses etcdraft, as Fabric does not support a
+// multi-node solo ordering service.
+func BuildConsensus(orderers []*Orderer, raftOptions *RaftOptions) *Consensus {
+	if len(orderers) <= 1 {
+		return &Consensus{Type: ConsensusTypeSolo}
+	}
+	if raftOptions == nil {
+		raftOptions = DefaultRaftOptions()
+	}
+	consenters := make([]*Consenter, len(orderers))
+	for i, o := range orderers {
+		consenters[i] = &Consenter{
+			MSPID:         o.MSPID(),
+			Host:          o.Hostname(),
+			Port:          o.ClusterPort(),
+			ClientTLSCert: o.TLSIdentity().Certificate().Bytes(),
+			ServerTLSCert: o.TLSIdentity().Certificate().Bytes(),
+		}
+	}
+	return &Consensus{
+		Type:        ConsensusTypeEtcdRaft,
+		RaftOptions: raftOptions,
+		Consenters:  consenters,
+	}
+}
@@ -0,0 +1,62 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package orderer
+
+import (
+	"github.com/pkg/errors"
+)
+
+// Group represents the set of orderer nodes that make up the ordering service for a
+// network, whether that is a single solo orderer or a multi-node etcdraft cluster.
+type Group struct {
+	orderers  []*Orderer
+	consensus *Consensus
+}
+
+// NewGroup creates a new orderer group from the supplied orderers. The consensus type
+// is derived automatically: a single orderer results in solo, more than one results in
+// etcdraft with raftOptions applied (or the defaults, if raftOptions is nil).
+func NewGroup(orderers []*Orderer, raftOptions *RaftOptions) (*Group, error) {
+	if len(orderers) == 0 {
+		return nil, errors.New("at least one orderer is required")
+	}
+	return &Group{orderers, BuildConsensus(orderers, raftOptions)}, nil
+}
+
+// Orderers returns the orderer nodes in the group.
+func (g *Group) Orderers() []*Orderer {
+	return g.orderers
+}
+
+// Consensus returns the consensus configuration for the group, for use when building
+// the channel genesis block.
+func (g *Group) Consensus() *Consensus {
+	return g.consensus
+}
+
+// Start starts every orderer node in the group.
+func (g *Group) Start() error {
+	started := make([]*Orderer, 0, len(g.orderers))
+	for _, o := range g.orderers {
+		if err := o.Start(g.consensus); err != nil {
+			for _, s := range started {
+				s.Stop()
+			}
+			return errors.WithMessage(err, "failed to start orderer group")
+		}
+		started = append(started, o)
+	}
+	return nil
+}
+
+// Stop stops every orderer node in the group.
+func (g *Group) Stop() error {
+	for _, o := range g.orderers {
+		if err := o.Stop(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package orderer
+
+import "github.com/IBM-Blockchain/microfab/internal/pkg/identity/bccsp"
+
+// options holds the optional configuration applied to an orderer by the functional
+// options passed into New.
+type options struct {
+	bccsp *bccsp.Config
+}
+
+// Option is a functional option that customizes an orderer created by New.
+type Option func(*options)
+
+// WithBCCSP selects the cryptographic service provider used to generate and store the
+// orderer's identity key, such as a PKCS#11 HSM. The default is the software provider.
+func WithBCCSP(config *bccsp.Config) Option {
+	return func(o *options) {
+		o.bccsp = config
+	}
+}
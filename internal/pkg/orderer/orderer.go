@@ -0,0 +1,350 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package orderer
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/IBM-Blockchain/microfab/internal/pkg/identity"
+	"github.com/IBM-Blockchain/microfab/internal/pkg/identity/bccsp"
+	"github.com/IBM-Blockchain/microfab/internal/pkg/organization"
+	"github.com/IBM-Blockchain/microfab/internal/pkg/util"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// Orderer represents a loaded orderer definition.
+type Orderer struct {
+	organization   *organization.Organization
+	identity       *identity.Identity
+	tlsIdentity    *identity.Identity
+	mspID          string
+	directory      string
+	apiPort        int32
+	apiURL         *url.URL
+	clusterPort    int32
+	clusterURL     *url.URL
+	operationsPort int32
+	operationsURL  *url.URL
+	options        options
+	command        *exec.Cmd
+}
+
+// New creates a new orderer. opts customizes optional behaviour, such as selecting a
+// PKCS#11 HSM backed BCCSP for the orderer's identity.
+func New(organization *organization.Organization, directory string, apiPort int32, apiURL string, clusterPort int32, clusterURL string, operationsPort int32, operationsURL string, opts ...Option) (*Orderer, error) {
+	o := options{bccsp: bccsp.DefaultConfig()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	tlsIdentityName := fmt.Sprintf("%s Orderer TLS", organization.Name())
+	tlsIdentity, err := identity.New(tlsIdentityName, identity.WithOrganizationalUnit("orderer"), identity.UsingSigner(organization.TLSCA()))
+	if err != nil {
+		return nil, err
+	}
+	identityOpts := []identity.Option{identity.WithOrganizationalUnit("orderer"), identity.UsingSigner(organization.CA())}
+	if o.bccsp.UsesHSM() {
+		ski, err := o.bccsp.PKCS11.GenerateECKey()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to generate HSM-backed orderer identity key")
+		}
+		identityOpts = append(identityOpts, identity.UsingHSMKey(o.bccsp.PKCS11.Library, o.bccsp.PKCS11.Label, o.bccsp.PKCS11.Pin, ski))
+	}
+	identityName := fmt.Sprintf("%s Orderer", organization.Name())
+	identity, err := identity.New(identityName, identityOpts...)
+	if err != nil {
+		return nil, err
+	}
+	parsedAPIURL, err := url.Parse(apiURL)
+	if err != nil {
+		return nil, err
+	}
+	parsedClusterURL, err := url.Parse(clusterURL)
+	if err != nil {
+		return nil, err
+	}
+	parsedOperationsURL, err := url.Parse(operationsURL)
+	if err != nil {
+		return nil, err
+	}
+	return &Orderer{organization, identity, tlsIdentity, organization.MSP().ID(), directory, apiPort, parsedAPIURL, clusterPort, parsedClusterURL, operationsPort, parsedOperationsURL, o, nil}, nil
+}
+
+// Organization returns the organization of the orderer.
+func (o *Orderer) Organization() *organization.Organization {
+	return o.organization
+}
+
+// MSPID returns the MSP ID of the orderer.
+func (o *Orderer) MSPID() string {
+	return o.mspID
+}
+
+// APIPort returns the API port of the orderer.
+func (o *Orderer) APIPort() int32 {
+	return o.apiPort
+}
+
+// APIURL returns the API URL of the orderer.
+func (o *Orderer) APIURL() *url.URL {
+	return o.apiURL
+}
+
+// ClusterPort returns the raft cluster port of the orderer.
+func (o *Orderer) ClusterPort() int32 {
+	return o.clusterPort
+}
+
+// ClusterURL returns the raft cluster URL of the orderer.
+func (o *Orderer) ClusterURL() *url.URL {
+	return o.clusterURL
+}
+
+// OperationsPort returns the operations port of the orderer.
+func (o *Orderer) OperationsPort() int32 {
+	return o.operationsPort
+}
+
+// OperationsURL returns the operations URL of the orderer.
+func (o *Orderer) OperationsURL() *url.URL {
+	return o.operationsURL
+}
+
+// Host returns the host (hostname:port) of the orderer.
+func (o *Orderer) Host() string {
+	return o.apiURL.Host
+}
+
+// Hostname returns the hostname of the orderer.
+func (o *Orderer) Hostname() string {
+	return o.apiURL.Hostname()
+}
+
+// Port returns the port of the orderer.
+func (o *Orderer) Port() int32 {
+	port, _ := strconv.Atoi(o.apiURL.Port())
+	return int32(port)
+}
+
+// Identity returns the enrollment identity of the orderer.
+func (o *Orderer) Identity() *identity.Identity {
+	return o.identity
+}
+
+// TLSIdentity returns the orderer's TLS identity, signed by the organization's TLS CA.
+// This is the identity used for the raft cluster's mutual-TLS handshake (the
+// consenter set's client/server TLS certs), as distinct from the enrollment identity
+// used to sign transactions.
+func (o *Orderer) TLSIdentity() *identity.Identity {
+	return o.tlsIdentity
+}
+
+func (o *Orderer) createDirectories() error {
+	directories := []string{
+		o.directory,
+		path.Join(o.directory, "config"),
+		path.Join(o.directory, "data"),
+		path.Join(o.directory, "logs"),
+		path.Join(o.directory, "msp"),
+		path.Join(o.directory, "tls"),
+	}
+	for _, dir := range directories {
+		err := os.MkdirAll(dir, 0755)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *Orderer) createConfig(dataDirectory, mspDirectory, tlsDirectory string, consensus *Consensus) error {
+	fabricConfigPath, ok := os.LookupEnv("FABRIC_CFG_PATH")
+	if !ok {
+		return fmt.Errorf("FABRIC_CFG_PATH not defined")
+	}
+	configFile := path.Join(fabricConfigPath, "orderer.yaml")
+	configData, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+	config := map[interface{}]interface{}{}
+	err = yaml.Unmarshal(configData, config)
+	if err != nil {
+		return err
+	}
+	general, ok := config["General"].(map[interface{}]interface{})
+	if !ok {
+		return fmt.Errorf("orderer.yaml missing General section")
+	}
+	general["ListenAddress"] = "0.0.0.0"
+	general["ListenPort"] = o.apiPort
+	general["LocalMSPID"] = o.mspID
+	general["LocalMSPDir"] = mspDirectory
+	general["BootstrapMethod"] = "none"
+	general["BCCSP"] = o.options.bccsp.Section()
+	cluster, ok := general["Cluster"].(map[interface{}]interface{})
+	if !ok {
+		cluster = map[interface{}]interface{}{}
+		general["Cluster"] = cluster
+	}
+	cluster["ListenAddress"] = "0.0.0.0"
+	cluster["ListenPort"] = o.clusterPort
+	cluster["ClientCertificate"] = path.Join(tlsDirectory, "server.crt")
+	cluster["ClientPrivateKey"] = path.Join(tlsDirectory, "server.key")
+	cluster["RootCAs"] = []string{path.Join(tlsDirectory, "ca.crt")}
+	tls, ok := general["TLS"].(map[interface{}]interface{})
+	if !ok {
+		tls = map[interface{}]interface{}{}
+		general["TLS"] = tls
+	}
+	tls["Enabled"] = true
+	tls["PrivateKey"] = path.Join(tlsDirectory, "server.key")
+	tls["Certificate"] = path.Join(tlsDirectory, "server.crt")
+	tls["RootCAs"] = []string{path.Join(tlsDirectory, "ca.crt")}
+	tls["ClientAuthRequired"] = true
+	tls["ClientRootCAs"] = []string{path.Join(tlsDirectory, "ca.crt")}
+	fileLedger, ok := config["FileLedger"].(map[interface{}]interface{})
+	if !ok {
+		return fmt.Errorf("orderer.yaml missing FileLedger section")
+	}
+	fileLedger["Location"] = path.Join(dataDirectory, "chains")
+	operations, ok := config["Operations"].(map[interface{}]interface{})
+	if !ok {
+		return fmt.Errorf("orderer.yaml missing Operations section")
+	}
+	operations["ListenAddress"] = fmt.Sprintf("0.0.0.0:%d", o.operationsPort)
+	metrics, ok := config["Metrics"].(map[interface{}]interface{})
+	if !ok {
+		return fmt.Errorf("orderer.yaml missing Metrics section")
+	}
+	metrics["Provider"] = "prometheus"
+	consensusSection, ok := config["Consensus"].(map[interface{}]interface{})
+	if !ok {
+		consensusSection = map[interface{}]interface{}{}
+		config["Consensus"] = consensusSection
+	}
+	if consensus != nil && consensus.Type == ConsensusTypeEtcdRaft {
+		consensusSection["WALDir"] = path.Join(dataDirectory, "etcdraft", "wal")
+		consensusSection["SnapDir"] = path.Join(dataDirectory, "etcdraft", "snapshot")
+	} else {
+		delete(config, "Consensus")
+	}
+	configData, err = yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	configFile = path.Join(o.directory, "config", "orderer.yaml")
+	return ioutil.WriteFile(configFile, configData, 0644)
+}
+
+func (o *Orderer) hasStarted() bool {
+	resp, err := http.Get(fmt.Sprintf("http://localhost:%d/healthz", o.operationsPort))
+	if err != nil {
+		return false
+	}
+	if resp.StatusCode != 200 {
+		return false
+	}
+	return true
+}
+
+// Start starts the orderer, using the supplied consensus configuration to select
+// between solo and etcdraft operation.
+func (o *Orderer) Start(consensus *Consensus) error {
+	err := o.createDirectories()
+	if err != nil {
+		return err
+	}
+	configDirectory := path.Join(o.directory, "config")
+	dataDirectory := path.Join(o.directory, "data")
+	logsDirectory := path.Join(o.directory, "logs")
+	mspDirectory := path.Join(o.directory, "msp")
+	tlsDirectory := path.Join(o.directory, "tls")
+	tlsCAChain := append(o.organization.MSP().TLSRootCertificates(), o.organization.MSP().TLSIntermediateCertificates()...)
+	err = util.CreateMSPDirectory(mspDirectory, o.identity, o.options.bccsp, tlsCAChain...)
+	if err != nil {
+		return err
+	}
+	err = util.WriteTLSKeyPair(tlsDirectory, o.tlsIdentity, tlsCAChain...)
+	if err != nil {
+		return err
+	}
+	err = o.createConfig(dataDirectory, mspDirectory, tlsDirectory, consensus)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command("orderer")
+	cmd.Env = os.Environ()
+	extraEnvs := []string{
+		fmt.Sprintf("FABRIC_CFG_PATH=%s", configDirectory),
+	}
+	cmd.Env = append(cmd.Env, extraEnvs...)
+	cmd.Stdin = nil
+	logFile, err := os.OpenFile(path.Join(logsDirectory, "orderer.log"), os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	pipe, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	go io.Copy(logFile, pipe)
+	cmd.Stderr = cmd.Stdout
+	err = cmd.Start()
+	if err != nil {
+		return err
+	}
+	o.command = cmd
+	errchan := make(chan error, 1)
+	go func() {
+		err = cmd.Wait()
+		if err != nil {
+			errchan <- err
+		}
+	}()
+	timeout := time.After(10 * time.Second)
+	tick := time.Tick(250 * time.Millisecond)
+	for {
+		select {
+		case <-timeout:
+			o.Stop()
+			return errors.New("timeout whilst waiting for orderer to start")
+		case err := <-errchan:
+			o.Stop()
+			return errors.WithMessage(err, "failed to start orderer")
+		case <-tick:
+			if o.hasStarted() {
+				return nil
+			}
+		}
+	}
+}
+
+// Stop stops the orderer.
+func (o *Orderer) Stop() error {
+	if o.command != nil {
+		err := o.command.Process.Kill()
+		if err != nil {
+			return errors.WithMessage(err, "failed to stop orderer")
+		}
+		o.command = nil
+	}
+	return nil
+}
+
+// Name returns a human readable name for the orderer, suitable for use in logging.
+func (o *Orderer) Name() string {
+	return strings.ToLower(fmt.Sprintf("%sorderer", o.organization.Name()))
+}
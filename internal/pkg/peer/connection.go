@@ -0,0 +1,194 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package peer
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/IBM-Blockchain/microfab/internal/pkg/identity"
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/common"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	pb "github.com/hyperledger/fabric-protos-go/peer"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Connection is a signed gRPC connection to a peer's administrative APIs, used to list
+// and join channels as a particular identity (typically the organization's admin).
+type Connection struct {
+	peer       *Peer
+	mspID      string
+	identity   *identity.Identity
+	connection *grpc.ClientConn
+	endorser   pb.EndorserClient
+}
+
+// Connect opens a connection to the supplied peer, signing requests as the supplied
+// MSP ID and identity. The connection is secured with the peer organization's TLS CA,
+// since the peer's gRPC endpoints run with TLS enabled.
+func Connect(p *Peer, mspID string, id *identity.Identity) (*Connection, error) {
+	creds, err := tlsCredentials(p)
+	if err != nil {
+		return nil, err
+	}
+	connection, err := grpc.Dial(p.Host(), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to connect to peer")
+	}
+	return &Connection{p, mspID, id, connection, pb.NewEndorserClient(connection)}, nil
+}
+
+// tlsCredentials builds client-side TLS credentials that trust the peer organization's
+// TLS CA chain and present the peer's own TLS identity as the client certificate, so
+// Connect can complete the mutual-TLS handshake the peer's TLS-enabled gRPC endpoint
+// requires.
+func tlsCredentials(p *Peer) (credentials.TransportCredentials, error) {
+	pool := x509.NewCertPool()
+	tlsCAChain := append(p.Organization().MSP().TLSRootCertificates(), p.Organization().MSP().TLSIntermediateCertificates()...)
+	for _, cert := range tlsCAChain {
+		if !pool.AppendCertsFromPEM(cert.Bytes()) {
+			return nil, errors.New("failed to add TLS CA certificate to trust pool")
+		}
+	}
+	clientCert, err := tls.X509KeyPair(p.tlsIdentity.Certificate().Bytes(), p.tlsIdentity.PrivateKey().Bytes())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to load client TLS key pair")
+	}
+	return credentials.NewTLS(&tls.Config{
+		RootCAs:      pool,
+		ServerName:   p.Hostname(),
+		Certificates: []tls.Certificate{clientCert},
+	}), nil
+}
+
+// Close closes the connection to the peer.
+func (c *Connection) Close() error {
+	return c.connection.Close()
+}
+
+// ListChannels lists the channels that the peer has joined, by invoking CSCC's
+// GetChannels.
+func (c *Connection) ListChannels() ([]string, error) {
+	response, err := c.invokeCSCC("GetChannels")
+	if err != nil {
+		return nil, err
+	}
+	channelsResponse := &pb.ChannelQueryResponse{}
+	if err := proto.Unmarshal(response, channelsResponse); err != nil {
+		return nil, errors.WithMessage(err, "failed to unmarshal channel query response")
+	}
+	channels := make([]string, len(channelsResponse.Channels))
+	for i, ci := range channelsResponse.Channels {
+		channels[i] = ci.ChannelId
+	}
+	return channels, nil
+}
+
+// JoinChannel joins the peer to the channel described by the supplied genesis block,
+// by invoking CSCC's JoinChain.
+func (c *Connection) JoinChannel(genesisBlock []byte) error {
+	_, err := c.invokeCSCC("JoinChain", genesisBlock)
+	return err
+}
+
+// invokeCSCC builds, signs, and sends a proposal invoking the named CSCC function with
+// the supplied arguments, and returns the raw response payload.
+func (c *Connection) invokeCSCC(fn string, args ...[]byte) ([]byte, error) {
+	proposal, txID, err := c.buildProposal(fn, args)
+	if err != nil {
+		return nil, err
+	}
+	proposalBytes, err := proto.Marshal(proposal)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to marshal proposal")
+	}
+	signature, err := c.identity.Sign(proposalBytes)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to sign proposal")
+	}
+	signedProposal := &pb.SignedProposal{ProposalBytes: proposalBytes, Signature: signature}
+	response, err := c.endorser.ProcessProposal(context.Background(), signedProposal)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to process %s proposal (txID %s)", fn, txID)
+	}
+	if response.Response == nil || response.Response.Status != 200 {
+		return nil, errors.Errorf("%s proposal failed: %s", fn, response.GetResponse().GetMessage())
+	}
+	payload := &pb.ProposalResponsePayload{}
+	if err := proto.Unmarshal(response.Payload, payload); err != nil {
+		return nil, errors.WithMessage(err, "failed to unmarshal proposal response payload")
+	}
+	action := &pb.ChaincodeAction{}
+	if err := proto.Unmarshal(payload.Extension, action); err != nil {
+		return nil, errors.WithMessage(err, "failed to unmarshal chaincode action")
+	}
+	return action.Response.Payload, nil
+}
+
+func (c *Connection) buildProposal(fn string, args [][]byte) (*pb.Proposal, string, error) {
+	creator, err := proto.Marshal(&msp.SerializedIdentity{Mspid: c.mspID, IdBytes: c.identity.Certificate().Bytes()})
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "failed to marshal creator identity")
+	}
+	nonce := make([]byte, 24)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, "", errors.WithMessage(err, "failed to generate nonce")
+	}
+	txID := computeTxID(nonce, creator)
+	invocationSpec := &pb.ChaincodeInvocationSpec{
+		ChaincodeSpec: &pb.ChaincodeSpec{
+			ChaincodeId: &pb.ChaincodeID{Name: "cscc"},
+			Input:       &pb.ChaincodeInput{Args: append([][]byte{[]byte(fn)}, args...)},
+		},
+	}
+	invocationSpecBytes, err := proto.Marshal(invocationSpec)
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "failed to marshal chaincode invocation spec")
+	}
+	chaincodeProposalPayload, err := proto.Marshal(&pb.ChaincodeProposalPayload{Input: invocationSpecBytes})
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "failed to marshal chaincode proposal payload")
+	}
+	channelHeader, err := proto.Marshal(&common.ChannelHeader{
+		Type:      int32(common.HeaderType_ENDORSER_TRANSACTION),
+		TxId:      txID,
+		ChannelId: "",
+		Extension: mustMarshalChaincodeHeaderExtension(),
+	})
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "failed to marshal channel header")
+	}
+	signatureHeader, err := proto.Marshal(&common.SignatureHeader{Creator: creator, Nonce: nonce})
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "failed to marshal signature header")
+	}
+	header, err := proto.Marshal(&common.Header{ChannelHeader: channelHeader, SignatureHeader: signatureHeader})
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "failed to marshal header")
+	}
+	return &pb.Proposal{Header: header, Payload: chaincodeProposalPayload}, txID, nil
+}
+
+func mustMarshalChaincodeHeaderExtension() []byte {
+	b, err := proto.Marshal(&pb.ChaincodeHeaderExtension{ChaincodeId: &pb.ChaincodeID{Name: "cscc"}})
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// computeTxID derives a transaction ID the same way Fabric does: the hex-encoded
+// SHA-256 digest of the nonce concatenated with the serialized creator identity.
+func computeTxID(nonce, creator []byte) string {
+	sum := sha256.Sum256(append(append([]byte{}, nonce...), creator...))
+	return fmt.Sprintf("%x", sum)
+}
@@ -0,0 +1,123 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+// Package discovery implements a minimal client for Fabric's discovery service,
+// letting callers query a peer for channel configuration, peer membership, and
+// endorsers for a chaincode without hardcoding network topology.
+package discovery
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/IBM-Blockchain/microfab/internal/pkg/identity"
+	"github.com/IBM-Blockchain/microfab/internal/pkg/peer"
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+)
+
+// Client queries a peer's discovery service using a signing identity, typically the
+// admin identity of the organization that owns the peer.
+type Client struct {
+	peer              *peer.Peer
+	mspID             string
+	identity          *identity.Identity
+	clientTLSCertHash []byte
+	connection        *grpc.ClientConn
+	client            discovery.DiscoveryClient
+}
+
+// Connect connects to the discovery service exposed by the supplied peer, signing
+// requests as the supplied MSP ID and identity. The connection is secured with the
+// peer organization's TLS CA, since discovery (like every other peer gRPC service)
+// runs with TLS enabled.
+func Connect(p *peer.Peer, mspID string, id *identity.Identity) (*Client, error) {
+	creds, clientTLSCertHash, err := tlsCredentials(p)
+	if err != nil {
+		return nil, err
+	}
+	connection, err := grpc.Dial(p.Host(), grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to connect to peer discovery service")
+	}
+	return &Client{p, mspID, id, clientTLSCertHash, connection, discovery.NewDiscoveryClient(connection)}, nil
+}
+
+// tlsCredentials builds client-side TLS credentials that trust the peer organization's
+// TLS CA chain and present the peer's own TLS identity as the client certificate, so
+// Connect can complete the mutual-TLS handshake the peer's TLS-enabled gRPC endpoint
+// requires. It also returns the SHA-256 hash of that client certificate, which the
+// discovery service expects in AuthInfo.ClientTlsCertHash to bind the request to this
+// TLS session.
+func tlsCredentials(p *peer.Peer) (credentials.TransportCredentials, []byte, error) {
+	pool := x509.NewCertPool()
+	tlsCAChain := append(p.Organization().MSP().TLSRootCertificates(), p.Organization().MSP().TLSIntermediateCertificates()...)
+	for _, cert := range tlsCAChain {
+		if !pool.AppendCertsFromPEM(cert.Bytes()) {
+			return nil, nil, errors.New("failed to add TLS CA certificate to trust pool")
+		}
+	}
+	tlsIdentity := p.TLSIdentity()
+	clientCert, err := tls.X509KeyPair(tlsIdentity.Certificate().Bytes(), tlsIdentity.PrivateKey().Bytes())
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to load client TLS key pair")
+	}
+	creds := credentials.NewTLS(&tls.Config{
+		RootCAs:      pool,
+		ServerName:   p.Hostname(),
+		Certificates: []tls.Certificate{clientCert},
+	})
+	hash := sha256.Sum256(clientCert.Certificate[0])
+	return creds, hash[:], nil
+}
+
+// Close closes the connection to the peer.
+func (c *Client) Close() error {
+	return c.connection.Close()
+}
+
+// Endorsers queries the peer for the set of endorsers that satisfy the endorsement
+// policy of the supplied chaincode (and, optionally, collection) on the named channel.
+func (c *Client) Endorsers(channel, chaincode, collection string) (*EndorsementDescriptor, error) {
+	request, err := c.buildRequest(channel, []*interestQuery{{chaincode: chaincode, collection: collection}})
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.client.Discover(context.Background(), request)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to query peer discovery service for endorsers")
+	}
+	return parseEndorsementDescriptor(response)
+}
+
+// Config queries the peer for the channel's configuration, including the orderer
+// endpoints and the MSPs of the channel members.
+func (c *Client) Config(channel string) (*ConfigResult, error) {
+	request, err := c.buildRequest(channel, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.client.Discover(context.Background(), request)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to query peer discovery service for config")
+	}
+	return parseConfigResult(response)
+}
+
+// Peers queries the peer for the set of peers that have joined the named channel.
+func (c *Client) Peers(channel string) ([]*PeerInfo, error) {
+	request, err := c.buildRequest(channel, nil)
+	if err != nil {
+		return nil, err
+	}
+	response, err := c.client.Discover(context.Background(), request)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to query peer discovery service for membership")
+	}
+	return parsePeerMembership(response)
+}
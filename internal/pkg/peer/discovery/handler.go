@@ -0,0 +1,42 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package discovery
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/IBM-Blockchain/microfab/internal/pkg/organization"
+	"github.com/IBM-Blockchain/microfab/internal/pkg/peer"
+)
+
+// EndorsersHandler returns an http.HandlerFunc suitable for mounting at
+// GET /ak/api/v1/discovery/{channel}/endorsers?chaincode=...&collection=... on the
+// microfab console. It queries the supplied peer's discovery service as the
+// organization's admin identity and returns the resulting endorsement descriptor as
+// JSON.
+func EndorsersHandler(p *peer.Peer, org *organization.Organization, channel string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		chaincode := r.URL.Query().Get("chaincode")
+		if chaincode == "" {
+			http.Error(w, "chaincode query parameter is required", http.StatusBadRequest)
+			return
+		}
+		collection := r.URL.Query().Get("collection")
+		client, err := Connect(p, org.MSP().ID(), org.Admin())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		defer client.Close()
+		descriptor, err := client.Endorsers(channel, chaincode, collection)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(descriptor)
+	}
+}
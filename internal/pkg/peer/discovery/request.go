@@ -0,0 +1,80 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package discovery
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-protos-go/msp"
+	"github.com/pkg/errors"
+)
+
+// interestQuery describes a single chaincode/collection pair that endorsers are being
+// requested for.
+type interestQuery struct {
+	chaincode  string
+	collection string
+}
+
+// buildRequest assembles and signs a discovery.Request for the named channel. When
+// queries is non-empty, an additional endorsers query is included for each entry;
+// otherwise the request only asks for the channel config and peer membership.
+func (c *Client) buildRequest(channel string, queries []*interestQuery) (*discovery.SignedRequest, error) {
+	serializedIdentity, err := proto.Marshal(&msp.SerializedIdentity{
+		Mspid:   c.mspID,
+		IdBytes: c.identity.Certificate().Bytes(),
+	})
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to marshal client identity")
+	}
+	authInfo := &discovery.AuthInfo{
+		ClientIdentity:    serializedIdentity,
+		ClientTlsCertHash: c.clientTLSCertHash,
+	}
+	req := &discovery.Request{
+		Authentication: authInfo,
+		Queries: []*discovery.Query{
+			{
+				Channel: channel,
+				Query:   &discovery.Query_ConfigQuery{ConfigQuery: &discovery.ConfigQuery{}},
+			},
+			{
+				Channel: channel,
+				Query:   &discovery.Query_PeerQuery{PeerQuery: &discovery.PeerMembershipQuery{}},
+			},
+		},
+	}
+	for _, q := range queries {
+		interest := &discovery.ChaincodeInterest{
+			Chaincodes: []*discovery.ChaincodeCall{
+				{Name: q.chaincode, CollectionNames: collectionNames(q.collection)},
+			},
+		}
+		req.Queries = append(req.Queries, &discovery.Query{
+			Channel: channel,
+			Query: &discovery.Query_CcQuery{
+				CcQuery: &discovery.ChaincodeQuery{
+					Interests: []*discovery.ChaincodeInterest{interest},
+				},
+			},
+		})
+	}
+	payload, err := proto.Marshal(req)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to marshal discovery request")
+	}
+	signature, err := c.identity.Sign(payload)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to sign discovery request")
+	}
+	return &discovery.SignedRequest{Payload: payload, Signature: signature}, nil
+}
+
+func collectionNames(collection string) []string {
+	if collection == "" {
+		return nil
+	}
+	return []string{collection}
+}
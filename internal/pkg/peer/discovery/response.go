@@ -0,0 +1,157 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package discovery
+
+import (
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/pkg/errors"
+)
+
+// PeerInfo is the JSON-shaped representation of a single peer as returned by a
+// membership or endorsers query.
+type PeerInfo struct {
+	MSPID        string `json:"mspId"`
+	Endpoint     string `json:"endpoint"`
+	Identity     []byte `json:"identity"`
+	LedgerHeight uint64 `json:"ledgerHeight,omitempty"`
+}
+
+// EndorsementLayout is one viable combination of peer groups that together satisfy the
+// chaincode's endorsement policy.
+type EndorsementLayout struct {
+	QuantitiesByGroup map[string]int `json:"quantitiesByGroup"`
+}
+
+// EndorsementDescriptor is the JSON-shaped response to an endorsers query: the set of
+// layouts that satisfy the endorsement policy, and the peers available in each group.
+type EndorsementDescriptor struct {
+	Chaincode string                 `json:"chaincode"`
+	Layouts   []*EndorsementLayout   `json:"layouts"`
+	Groups    map[string][]*PeerInfo `json:"groups"`
+}
+
+// ConfigResult is the JSON-shaped response to a config query: the orderer host names
+// (without ports) by MSP ID, and the MSP IDs of the channel members.
+type ConfigResult struct {
+	Orderers map[string][]string `json:"orderers"`
+	MSPs     []string            `json:"msps"`
+}
+
+func parseEndorsementDescriptor(resp *discovery.Response) (*EndorsementDescriptor, error) {
+	for _, result := range resp.Results {
+		ccResult, ok := result.Result.(*discovery.QueryResult_CcQueryRes)
+		if !ok {
+			continue
+		}
+		if len(ccResult.CcQueryRes.Content) == 0 {
+			continue
+		}
+		descriptor := ccResult.CcQueryRes.Content[0]
+		layouts := make([]*EndorsementLayout, len(descriptor.Layouts))
+		for i, l := range descriptor.Layouts {
+			layouts[i] = &EndorsementLayout{QuantitiesByGroup: map[string]int{}}
+			for group, quantity := range l.QuantitiesByGroup {
+				layouts[i].QuantitiesByGroup[group] = int(quantity)
+			}
+		}
+		groups := map[string][]*PeerInfo{}
+		for group, endorsers := range descriptor.EndorsersByGroups {
+			peers := make([]*PeerInfo, len(endorsers.Peers))
+			for i, p := range endorsers.Peers {
+				peers[i] = &PeerInfo{Identity: p.Identity}
+			}
+			groups[group] = peers
+		}
+		return &EndorsementDescriptor{
+			Chaincode: descriptor.Chaincode,
+			Layouts:   layouts,
+			Groups:    groups,
+		}, nil
+	}
+	return nil, errors.New("discovery response did not contain an endorsers result")
+}
+
+func parseConfigResult(resp *discovery.Response) (*ConfigResult, error) {
+	for _, result := range resp.Results {
+		configResult, ok := result.Result.(*discovery.QueryResult_ConfigResult)
+		if !ok {
+			continue
+		}
+		orderers := map[string][]string{}
+		for mspID, endpoints := range configResult.ConfigResult.Orderers {
+			hosts := make([]string, len(endpoints.Endpoint))
+			for i, e := range endpoints.Endpoint {
+				hosts[i] = e.Host
+			}
+			orderers[mspID] = hosts
+		}
+		msps := make([]string, 0, len(configResult.ConfigResult.Msps))
+		for mspID := range configResult.ConfigResult.Msps {
+			msps = append(msps, mspID)
+		}
+		return &ConfigResult{Orderers: orderers, MSPs: msps}, nil
+	}
+	return nil, errors.New("discovery response did not contain a config result")
+}
+
+func parsePeerMembership(resp *discovery.Response) ([]*PeerInfo, error) {
+	for _, result := range resp.Results {
+		membersResult, ok := result.Result.(*discovery.QueryResult_Members)
+		if !ok {
+			continue
+		}
+		peers := []*PeerInfo{}
+		for mspID, members := range membersResult.Members.PeersByOrg {
+			for _, p := range members.Peers {
+				info := &PeerInfo{MSPID: mspID, Identity: p.Identity}
+				if endpoint, err := parseMembershipEndpoint(p.MembershipInfo); err == nil {
+					info.Endpoint = endpoint
+				}
+				if height, err := parseLedgerHeight(p.StateInfo); err == nil {
+					info.LedgerHeight = height
+				}
+				peers = append(peers, info)
+			}
+		}
+		return peers, nil
+	}
+	return nil, errors.New("discovery response did not contain a membership result")
+}
+
+// parseMembershipEndpoint extracts the peer's externally reachable endpoint from the
+// gossip alive message carried in its membership_info envelope.
+func parseMembershipEndpoint(envelope *gossip.Envelope) (string, error) {
+	if envelope == nil {
+		return "", errors.New("no membership info in discovery response")
+	}
+	msg := &gossip.GossipMessage{}
+	if err := proto.Unmarshal(envelope.Payload, msg); err != nil {
+		return "", errors.WithMessage(err, "failed to unmarshal gossip membership message")
+	}
+	aliveMsg := msg.GetAliveMsg()
+	if aliveMsg == nil || aliveMsg.Membership == nil {
+		return "", errors.New("gossip membership message did not contain an alive message")
+	}
+	return aliveMsg.Membership.Endpoint, nil
+}
+
+// parseLedgerHeight extracts the peer's reported ledger height from the gossip state
+// info message carried in its state_info envelope.
+func parseLedgerHeight(envelope *gossip.Envelope) (uint64, error) {
+	if envelope == nil {
+		return 0, errors.New("no state info in discovery response")
+	}
+	msg := &gossip.GossipMessage{}
+	if err := proto.Unmarshal(envelope.Payload, msg); err != nil {
+		return 0, errors.WithMessage(err, "failed to unmarshal gossip state info message")
+	}
+	stateInfo := msg.GetStateInfo()
+	if stateInfo == nil || stateInfo.Properties == nil {
+		return 0, errors.New("gossip state info message did not contain properties")
+	}
+	return stateInfo.Properties.LedgerHeight, nil
+}
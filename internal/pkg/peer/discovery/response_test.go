@@ -0,0 +1,152 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package discovery
+
+import (
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/hyperledger/fabric-protos-go/discovery"
+	"github.com/hyperledger/fabric-protos-go/gossip"
+	"github.com/hyperledger/fabric-protos-go/msp"
+)
+
+func TestParseEndorsementDescriptor(t *testing.T) {
+	resp := &discovery.Response{
+		Results: []*discovery.QueryResult{
+			{
+				Result: &discovery.QueryResult_CcQueryRes{
+					CcQueryRes: &discovery.ChaincodeQueryResult{
+						Content: []*discovery.EndorsementDescriptor{
+							{
+								Chaincode: "mycc",
+								Layouts: []*discovery.EndorsementLayout{
+									{QuantitiesByGroup: map[string]uint32{"g1": 2}},
+								},
+								EndorsersByGroups: map[string]*discovery.Peers{
+									"g1": {Peers: []*discovery.Peer{{Identity: []byte("cert0")}}},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	descriptor, err := parseEndorsementDescriptor(resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if descriptor.Chaincode != "mycc" {
+		t.Errorf("expected chaincode mycc, got %s", descriptor.Chaincode)
+	}
+	if len(descriptor.Layouts) != 1 || descriptor.Layouts[0].QuantitiesByGroup["g1"] != 2 {
+		t.Errorf("unexpected layouts: %+v", descriptor.Layouts)
+	}
+	peers, ok := descriptor.Groups["g1"]
+	if !ok || len(peers) != 1 || string(peers[0].Identity) != "cert0" {
+		t.Errorf("unexpected groups: %+v", descriptor.Groups)
+	}
+}
+
+func TestParseEndorsementDescriptorNoResult(t *testing.T) {
+	_, err := parseEndorsementDescriptor(&discovery.Response{})
+	if err == nil {
+		t.Fatal("expected error for a response with no endorsers result")
+	}
+}
+
+func TestParseConfigResult(t *testing.T) {
+	resp := &discovery.Response{
+		Results: []*discovery.QueryResult{
+			{
+				Result: &discovery.QueryResult_ConfigResult{
+					ConfigResult: &discovery.ConfigResult{
+						Orderers: map[string]*discovery.Endpoints{
+							"OrdererMSP": {Endpoint: []*discovery.Endpoint{{Host: "orderer0.example.com", Port: 7050}}},
+						},
+						Msps: map[string]*msp.FabricMSPConfig{
+							"Org1MSP": {},
+						},
+					},
+				},
+			},
+		},
+	}
+	result, err := parseConfigResult(resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if hosts := result.Orderers["OrdererMSP"]; len(hosts) != 1 || hosts[0] != "orderer0.example.com" {
+		t.Errorf("unexpected orderers: %+v", result.Orderers)
+	}
+	if len(result.MSPs) != 1 || result.MSPs[0] != "Org1MSP" {
+		t.Errorf("unexpected msps: %+v", result.MSPs)
+	}
+}
+
+func TestParsePeerMembership(t *testing.T) {
+	aliveMsg := mustMarshal(t, &gossip.GossipMessage{
+		Content: &gossip.GossipMessage_AliveMsg{
+			AliveMsg: &gossip.AliveMessage{
+				Membership: &gossip.Member{Endpoint: "peer0.org1.example.com:7051"},
+			},
+		},
+	})
+	stateInfoMsg := mustMarshal(t, &gossip.GossipMessage{
+		Content: &gossip.GossipMessage_StateInfo{
+			StateInfo: &gossip.StateInfo{
+				Properties: &gossip.Properties{LedgerHeight: 42},
+			},
+		},
+	})
+	resp := &discovery.Response{
+		Results: []*discovery.QueryResult{
+			{
+				Result: &discovery.QueryResult_Members{
+					Members: &discovery.PeerMembershipResult{
+						PeersByOrg: map[string]*discovery.Peers{
+							"Org1MSP": {
+								Peers: []*discovery.Peer{
+									{
+										Identity:       []byte("cert0"),
+										MembershipInfo: &gossip.Envelope{Payload: aliveMsg},
+										StateInfo:      &gossip.Envelope{Payload: stateInfoMsg},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	peers, err := parsePeerMembership(resp)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(peers) != 1 {
+		t.Fatalf("expected 1 peer, got %d", len(peers))
+	}
+	peer := peers[0]
+	if peer.MSPID != "Org1MSP" {
+		t.Errorf("expected MSPID Org1MSP, got %s", peer.MSPID)
+	}
+	if peer.Endpoint != "peer0.org1.example.com:7051" {
+		t.Errorf("expected endpoint to be populated, got %q", peer.Endpoint)
+	}
+	if peer.LedgerHeight != 42 {
+		t.Errorf("expected ledger height 42, got %d", peer.LedgerHeight)
+	}
+}
+
+func mustMarshal(t *testing.T, m proto.Message) []byte {
+	t.Helper()
+	b, err := proto.Marshal(m)
+	if err != nil {
+		t.Fatalf("failed to marshal %T: %v", m, err)
+	}
+	return b
+}
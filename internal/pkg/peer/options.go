@@ -0,0 +1,35 @@
+/*
+ * SPDX-License-Identifier: Apache-2.0
+ */
+
+package peer
+
+import "github.com/IBM-Blockchain/microfab/internal/pkg/identity/bccsp"
+
+// options holds the optional configuration applied to a peer by the functional options
+// passed into New.
+type options struct {
+	ccaasBuilder bool
+	bccsp        *bccsp.Config
+}
+
+// Option is a functional option that customizes a peer created by New.
+type Option func(*options)
+
+// WithCCaaSBuilder enables the chaincode-as-a-service external builder on the peer, in
+// addition to the golang/java/node/external-service-builder builders that are always
+// registered. Chaincode packaged with a connection.json is then dialled as an external
+// chaincode server instead of being built and launched locally.
+func WithCCaaSBuilder() Option {
+	return func(o *options) {
+		o.ccaasBuilder = true
+	}
+}
+
+// WithBCCSP selects the cryptographic service provider used to generate and store the
+// peer's identity key, such as a PKCS#11 HSM. The default is the software provider.
+func WithBCCSP(config *bccsp.Config) Option {
+	return func(o *options) {
+		o.bccsp = config
+	}
+}
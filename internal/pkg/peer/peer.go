@@ -18,6 +18,7 @@ import (
 	"time"
 
 	"github.com/IBM-Blockchain/microfab/internal/pkg/identity"
+	"github.com/IBM-Blockchain/microfab/internal/pkg/identity/bccsp"
 	"github.com/IBM-Blockchain/microfab/internal/pkg/organization"
 	"github.com/IBM-Blockchain/microfab/internal/pkg/util"
 	"github.com/pkg/errors"
@@ -28,6 +29,7 @@ import (
 type Peer struct {
 	organization   *organization.Organization
 	identity       *identity.Identity
+	tlsIdentity    *identity.Identity
 	mspID          string
 	directory      string
 	apiPort        int32
@@ -36,13 +38,35 @@ type Peer struct {
 	chaincodeURL   *url.URL
 	operationsPort int32
 	operationsURL  *url.URL
+	peersInOrg     int32
+	options        options
 	command        *exec.Cmd
 }
 
-// New creates a new peer.
-func New(organization *organization.Organization, directory string, apiPort int32, apiURL string, chaincodePort int32, chaincodeURL string, operationsPort int32, operationsURL string) (*Peer, error) {
+// New creates a new peer. peersInOrg is the total number of peers being started for
+// this peer's organization; when it is greater than one, gossip leader election is
+// switched on instead of the static orgLeader used for a single peer per org. opts
+// customizes optional behaviour, such as registering the CCaaS external builder.
+func New(organization *organization.Organization, directory string, apiPort int32, apiURL string, chaincodePort int32, chaincodeURL string, operationsPort int32, operationsURL string, peersInOrg int32, opts ...Option) (*Peer, error) {
+	o := options{bccsp: bccsp.DefaultConfig()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	tlsIdentityName := fmt.Sprintf("%s Peer TLS", organization.Name())
+	tlsIdentity, err := identity.New(tlsIdentityName, identity.WithOrganizationalUnit("peer"), identity.UsingSigner(organization.TLSCA()))
+	if err != nil {
+		return nil, err
+	}
+	identityOpts := []identity.Option{identity.WithOrganizationalUnit("peer"), identity.UsingSigner(organization.CA())}
+	if o.bccsp.UsesHSM() {
+		ski, err := o.bccsp.PKCS11.GenerateECKey()
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to generate HSM-backed peer identity key")
+		}
+		identityOpts = append(identityOpts, identity.UsingHSMKey(o.bccsp.PKCS11.Library, o.bccsp.PKCS11.Label, o.bccsp.PKCS11.Pin, ski))
+	}
 	identityName := fmt.Sprintf("%s Peer", organization.Name())
-	identity, err := identity.New(identityName, identity.WithOrganizationalUnit("peer"), identity.UsingSigner(organization.CA()))
+	identity, err := identity.New(identityName, identityOpts...)
 	if err != nil {
 		return nil, err
 	}
@@ -58,7 +82,10 @@ func New(organization *organization.Organization, directory string, apiPort int3
 	if err != nil {
 		return nil, err
 	}
-	return &Peer{organization, identity, organization.MSP().ID(), directory, apiPort, parsedAPIURL, chaincodePort, parsedChaincodeURL, operationsPort, parsedOperationsURL, nil}, nil
+	if peersInOrg <= 0 {
+		peersInOrg = 1
+	}
+	return &Peer{organization, identity, tlsIdentity, organization.MSP().ID(), directory, apiPort, parsedAPIURL, chaincodePort, parsedChaincodeURL, operationsPort, parsedOperationsURL, peersInOrg, o, nil}, nil
 }
 
 // Organization returns the organization of the peer.
@@ -117,6 +144,13 @@ func (p *Peer) Port() int32 {
 	return int32(port)
 }
 
+// TLSIdentity returns the peer's TLS identity, signed by the organization's TLS CA.
+// This is the identity the peer's gRPC endpoints present during the TLS handshake, as
+// distinct from the enrollment identity used to sign transactions.
+func (p *Peer) TLSIdentity() *identity.Identity {
+	return p.tlsIdentity
+}
+
 func (p *Peer) createDirectories() error {
 	directories := []string{
 		p.directory,
@@ -124,6 +158,7 @@ func (p *Peer) createDirectories() error {
 		path.Join(p.directory, "data"),
 		path.Join(p.directory, "logs"),
 		path.Join(p.directory, "msp"),
+		path.Join(p.directory, "tls"),
 	}
 	for _, dir := range directories {
 		err := os.MkdirAll(dir, 0755)
@@ -134,7 +169,7 @@ func (p *Peer) createDirectories() error {
 	return nil
 }
 
-func (p *Peer) createConfig(dataDirectory, mspDirectory string) error {
+func (p *Peer) createConfig(dataDirectory, mspDirectory, tlsDirectory string) error {
 	fabricConfigPath, ok := os.LookupEnv("FABRIC_CFG_PATH")
 	if !ok {
 		return fmt.Errorf("FABRIC_CFG_PATH not defined")
@@ -160,15 +195,45 @@ func (p *Peer) createConfig(dataDirectory, mspDirectory string) error {
 	peer["address"] = fmt.Sprintf("0.0.0.0:%d", p.apiPort)
 	peer["listenAddress"] = fmt.Sprintf("0.0.0.0:%d", p.apiPort)
 	peer["chaincodeListenAddress"] = fmt.Sprintf("0.0.0.0:%d", p.chaincodePort)
+	peer["BCCSP"] = p.options.bccsp.Section()
 	gossip, ok := peer["gossip"].(map[interface{}]interface{})
 	if !ok {
 		return fmt.Errorf("core.yaml missing peer.gossip section")
 	}
 	gossip["bootstrap"] = p.apiURL.Host
-	gossip["useLeaderElection"] = false
-	gossip["orgLeader"] = true
+	if p.peersInOrg > 1 {
+		gossip["useLeaderElection"] = true
+		gossip["orgLeader"] = false
+	} else {
+		gossip["useLeaderElection"] = false
+		gossip["orgLeader"] = true
+	}
 	gossip["endpoint"] = p.apiURL.Host
 	gossip["externalEndpoint"] = p.apiURL.Host
+	tls, ok := peer["tls"].(map[interface{}]interface{})
+	if !ok {
+		return fmt.Errorf("core.yaml missing peer.tls section")
+	}
+	tls["enabled"] = true
+	tls["clientAuthRequired"] = true
+	cert, ok := tls["cert"].(map[interface{}]interface{})
+	if !ok {
+		cert = map[interface{}]interface{}{}
+		tls["cert"] = cert
+	}
+	cert["file"] = path.Join(tlsDirectory, "server.crt")
+	key, ok := tls["key"].(map[interface{}]interface{})
+	if !ok {
+		key = map[interface{}]interface{}{}
+		tls["key"] = key
+	}
+	key["file"] = path.Join(tlsDirectory, "server.key")
+	clientRootCAs, ok := tls["clientRootCAs"].(map[interface{}]interface{})
+	if !ok {
+		clientRootCAs = map[interface{}]interface{}{}
+		tls["clientRootCAs"] = clientRootCAs
+	}
+	clientRootCAs["files"] = []string{path.Join(tlsDirectory, "ca.crt")}
 	metrics, ok := config["metrics"].(map[interface{}]interface{})
 	if !ok {
 		return fmt.Errorf("core.yaml missing metrics section")
@@ -192,7 +257,7 @@ func (p *Peer) createConfig(dataDirectory, mspDirectory string) error {
 	if err != nil {
 		return err
 	}
-	chaincode["externalBuilders"] = []map[interface{}]interface{}{
+	externalBuilders := []map[interface{}]interface{}{
 		{
 			"path": path.Join(homeDirectory, "builders", "golang"),
 			"name": "golang",
@@ -228,6 +293,16 @@ func (p *Peer) createConfig(dataDirectory, mspDirectory string) error {
 			},
 		},
 	}
+	if p.options.ccaasBuilder {
+		externalBuilders = append(externalBuilders, map[interface{}]interface{}{
+			"path": path.Join(homeDirectory, "builders", "ccaas"),
+			"name": "ccaas-builder",
+			"propagateEnvironment": []string{
+				"HOME",
+			},
+		})
+	}
+	chaincode["externalBuilders"] = externalBuilders
 	configData, err = yaml.Marshal(config)
 	if err != nil {
 		return err
@@ -266,11 +341,17 @@ func (p *Peer) Start() error {
 	dataDirectory := path.Join(p.directory, "data")
 	logsDirectory := path.Join(p.directory, "logs")
 	mspDirectory := path.Join(p.directory, "msp")
-	err = util.CreateMSPDirectory(mspDirectory, p.identity)
+	tlsDirectory := path.Join(p.directory, "tls")
+	tlsCAChain := append(p.organization.MSP().TLSRootCertificates(), p.organization.MSP().TLSIntermediateCertificates()...)
+	err = util.CreateMSPDirectory(mspDirectory, p.identity, p.options.bccsp, tlsCAChain...)
+	if err != nil {
+		return err
+	}
+	err = util.WriteTLSKeyPair(tlsDirectory, p.tlsIdentity, tlsCAChain...)
 	if err != nil {
 		return err
 	}
-	err = p.createConfig(dataDirectory, mspDirectory)
+	err = p.createConfig(dataDirectory, mspDirectory, tlsDirectory)
 	if err != nil {
 		return err
 	}
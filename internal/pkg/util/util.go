@@ -5,11 +5,14 @@
 package util
 
 import (
+	"fmt"
 	"io/ioutil"
 	"os"
 	"path"
 
-	"github.com/IBM-Blockchain/fablet/internal/pkg/identity"
+	"github.com/IBM-Blockchain/microfab/internal/pkg/identity"
+	"github.com/IBM-Blockchain/microfab/internal/pkg/identity/bccsp"
+	"github.com/IBM-Blockchain/microfab/internal/pkg/identity/certificate"
 	"github.com/golang/protobuf/proto"
 )
 
@@ -38,14 +41,25 @@ const config = `NodeOUs:
     OrganizationalUnitIdentifier: orderer
 `
 
-// CreateMSPDirectory creates an MSP directory on disk suitable for the peer or orderer to use.
-func CreateMSPDirectory(directory string, identity *identity.Identity) error {
+// CreateMSPDirectory creates an MSP directory on disk suitable for the peer or orderer
+// to use. bccspConfig selects where the identity's private key lives: for the default
+// software provider it is written to keystore/key.pem as before; for a PKCS#11
+// provider the key already exists in the HSM and no keystore/ entry is written.
+// tlsCAChain is the TLS CA root certificate followed by any TLS CA intermediate
+// certificates; it may be omitted if the organization does not use a dedicated TLS CA,
+// in which case no tlscacerts/tlsintermediatecerts are written.
+func CreateMSPDirectory(directory string, id *identity.Identity, bccspConfig *bccsp.Config, tlsCAChain ...*certificate.Certificate) error {
 	directories := []string{
 		directory,
 		path.Join(directory, "admincerts"),
 		path.Join(directory, "cacerts"),
-		path.Join(directory, "keystore"),
+		path.Join(directory, "intermediatecerts"),
 		path.Join(directory, "signcerts"),
+		path.Join(directory, "tlscacerts"),
+		path.Join(directory, "tlsintermediatecerts"),
+	}
+	if !bccspConfig.UsesHSM() {
+		directories = append(directories, path.Join(directory, "keystore"))
 	}
 	for _, directory := range directories {
 		err := os.MkdirAll(directory, 0755)
@@ -57,19 +71,72 @@ func CreateMSPDirectory(directory string, identity *identity.Identity) error {
 	if err != nil {
 		return err
 	}
-	privateKey := identity.PrivateKey().Bytes()
-	err = ioutil.WriteFile(path.Join(directory, "keystore", "key.pem"), privateKey, 0644)
+	if !bccspConfig.UsesHSM() {
+		privateKey := id.PrivateKey().Bytes()
+		err = ioutil.WriteFile(path.Join(directory, "keystore", "key.pem"), privateKey, 0644)
+		if err != nil {
+			return err
+		}
+	}
+	cert := id.Certificate().Bytes()
+	err = ioutil.WriteFile(path.Join(directory, "signcerts", "cert.pem"), cert, 0644)
 	if err != nil {
 		return err
 	}
-	certificate := identity.Certificate().Bytes()
-	err = ioutil.WriteFile(path.Join(directory, "signcerts", "cert.pem"), certificate, 0644)
+	if hasCA := id.CA() != nil; hasCA {
+		ca := id.CA().Bytes()
+		err = ioutil.WriteFile(path.Join(directory, "cacerts", "ca.pem"), ca, 0644)
+		if err != nil {
+			return err
+		}
+		for i, intermediate := range id.IntermediateCAs() {
+			name := fmt.Sprintf("intermediate-%d.pem", i)
+			err = ioutil.WriteFile(path.Join(directory, "intermediatecerts", name), intermediate.Bytes(), 0644)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if len(tlsCAChain) > 0 {
+		err = ioutil.WriteFile(path.Join(directory, "tlscacerts", "tlsca.pem"), tlsCAChain[0].Bytes(), 0644)
+		if err != nil {
+			return err
+		}
+		for i, intermediate := range tlsCAChain[1:] {
+			name := fmt.Sprintf("tlsintermediate-%d.pem", i)
+			err = ioutil.WriteFile(path.Join(directory, "tlsintermediatecerts", name), intermediate.Bytes(), 0644)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WriteTLSKeyPair writes a TLS identity's certificate and private key, together with the
+// CA chain that verifies it, to directory. This is the set of files a peer or orderer
+// points its TLS section at so it can terminate TLS on its gRPC endpoints and, with
+// ClientRootCAs configured from the same chain, authenticate its own organization's
+// other nodes for mutual TLS.
+func WriteTLSKeyPair(directory string, id *identity.Identity, tlsCAChain ...*certificate.Certificate) error {
+	err := os.MkdirAll(directory, 0755)
 	if err != nil {
 		return err
 	}
-	if hasCA := identity.CA() != nil; hasCA {
-		ca := identity.CA().Bytes()
-		err = ioutil.WriteFile(path.Join(directory, "cacerts", "ca.pem"), ca, 0644)
+	err = ioutil.WriteFile(path.Join(directory, "server.crt"), id.Certificate().Bytes(), 0644)
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(path.Join(directory, "server.key"), id.PrivateKey().Bytes(), 0644)
+	if err != nil {
+		return err
+	}
+	for i, ca := range tlsCAChain {
+		name := "ca.crt"
+		if i > 0 {
+			name = fmt.Sprintf("ca-%d.crt", i)
+		}
+		err = ioutil.WriteFile(path.Join(directory, name), ca.Bytes(), 0644)
 		if err != nil {
 			return err
 		}